@@ -0,0 +1,109 @@
+package cemi
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// MsgCodeBusmonInd is the cEMI message code for L_Busmon.ind, the message a TUNNEL_BUSMONITOR
+// connection delivers for every frame observed on the bus.
+const MsgCodeBusmonInd uint8 = 0x2b
+
+// Additional Info Type IDs that can appear in a L_Busmon.ind's additional-info block. Only the
+// two consumed by BusmonInd are named; unrecognized TLVs are skipped.
+const (
+	addInfoTypeBusmonitorStatus uint8 = 0x03
+	addInfoTypeTimestamp        uint8 = 0x04
+)
+
+// BusmonitorStatus is the additional-info TLV a L_Busmon.ind carries alongside the frame,
+// describing the physical condition under which it was captured, including conditions
+// (checksum failures, bus contention) that a TUNNEL_LINKLAYER connection would have filtered
+// out and never delivered.
+type BusmonitorStatus struct {
+	FrameError     bool
+	BitError       bool
+	ParityError    bool
+	Overflow       bool
+	Lost           bool
+	SequenceNumber uint8
+}
+
+// BusmonInd is the cEMI message delivered over a TUNNEL_BUSMONITOR connection: a raw observation
+// of a single frame on the bus, with no guarantee that it was valid or addressed to anyone.
+type BusmonInd struct {
+	// Status describes the physical condition the frame was received under, taken from the
+	// additional-info busmonitor status TLV.
+	Status BusmonitorStatus
+
+	// Timestamp is the gateway's local timer value, in bit periods, at which the frame was
+	// captured, taken from the additional-info timestamp TLV.
+	Timestamp uint32
+
+	// Payload is the raw frame as seen on the bus.
+	Payload []byte
+}
+
+// Unpack parses a L_Busmon.ind cEMI message: the message code, the additional-info block
+// (picking out the busmonitor status and timestamp TLVs, skipping any others), and the raw
+// frame that follows. It returns the number of bytes consumed from data.
+func (ind *BusmonInd) Unpack(data []byte) (uint, error) {
+	if len(data) < 2 {
+		return 0, errors.New("cemi: L_Busmon.ind is too short")
+	}
+
+	if data[0] != MsgCodeBusmonInd {
+		return 0, errors.New("cemi: not a L_Busmon.ind message")
+	}
+
+	addInfoLen := uint(data[1])
+	if uint(len(data)) < 2+addInfoLen {
+		return 0, errors.New("cemi: L_Busmon.ind additional info is truncated")
+	}
+
+	info := data[2 : 2+addInfoLen]
+
+	for len(info) > 0 {
+		if len(info) < 2 {
+			return 0, errors.New("cemi: malformed additional info TLV header")
+		}
+
+		tlvType := info[0]
+		tlvLen := uint(info[1])
+
+		if uint(len(info)) < 2+tlvLen {
+			return 0, errors.New("cemi: additional info TLV is truncated")
+		}
+
+		value := info[2 : 2+tlvLen]
+
+		switch tlvType {
+		case addInfoTypeBusmonitorStatus:
+			if tlvLen != 1 {
+				return 0, errors.New("cemi: malformed busmonitor status TLV")
+			}
+
+			ind.Status = BusmonitorStatus{
+				FrameError:     value[0]&0x80 != 0,
+				BitError:       value[0]&0x40 != 0,
+				ParityError:    value[0]&0x20 != 0,
+				Overflow:       value[0]&0x10 != 0,
+				Lost:           value[0]&0x08 != 0,
+				SequenceNumber: value[0] & 0x07,
+			}
+
+		case addInfoTypeTimestamp:
+			if tlvLen != 4 {
+				return 0, errors.New("cemi: malformed timestamp TLV")
+			}
+
+			ind.Timestamp = binary.BigEndian.Uint32(value)
+		}
+
+		info = info[2+tlvLen:]
+	}
+
+	ind.Payload = append([]byte(nil), data[2+addInfoLen:]...)
+
+	return uint(len(data)), nil
+}
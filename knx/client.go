@@ -24,6 +24,16 @@ type ClientConfig struct {
 	// ResponseTimeout specifies how long to wait for a response. A timeout <= 0 will not be
 	// accepted. Instead, the default value will be used.
 	ResponseTimeout time.Duration
+
+	// MaxBackoff caps the exponential backoff used by a Dial-ed client while it is
+	// reconnecting. A value <= 0 will result in the use of a default value. Unused by Connect.
+	MaxBackoff time.Duration
+
+	// ConnType selects which kind of tunnel connection to open: the standard link-layer tunnel
+	// (the default, proto.ConnTypeTunnel), a raw cEMI tunnel, or a receive-only busmonitor
+	// tunnel. The zero value behaves as proto.ConnTypeTunnel. A busmonitor Client rejects Send
+	// with ErrBusmonitorReadOnly.
+	ConnType proto.ConnType
 }
 
 // Default configuration elements
@@ -31,11 +41,13 @@ var (
 	defaultResendInterval  = 500 * time.Millisecond
 	defaultHeartbeatDelay  = 10 * time.Second
 	defaultResponseTimeout = 10 * time.Second
+	defaultMaxBackoff      = time.Minute
 
 	DefaultClientConfig = ClientConfig{
-		defaultResendInterval,
-		defaultHeartbeatDelay,
-		defaultResponseTimeout,
+		ResendInterval:  defaultResendInterval,
+		HeartbeatDelay:  defaultHeartbeatDelay,
+		ResponseTimeout: defaultResponseTimeout,
+		MaxBackoff:      defaultMaxBackoff,
 	}
 )
 
@@ -53,9 +65,31 @@ func checkClientConfig(config ClientConfig) ClientConfig {
 		config.ResponseTimeout = defaultResponseTimeout
 	}
 
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = defaultMaxBackoff
+	}
+
+	if config.ConnType == 0 {
+		config.ConnType = proto.ConnTypeTunnel
+	}
+
 	return config
 }
 
+// ErrBusmonitorReadOnly is returned by Client.Send when the underlying connection is a
+// TUNNEL_BUSMONITOR tunnel, which can only observe the bus and never inject frames onto it.
+var ErrBusmonitorReadOnly = errors.New("knx: busmonitor connections are receive-only")
+
+// ErrShuttingDown is returned by requestTunnel (and therefore Client.Send) once a graceful
+// shutdown has been initiated via Client.Shutdown. The gateway is about to tear down the
+// channel, so starting a new resend cycle would be pointless.
+var ErrShuttingDown = errors.New("knx: client is shutting down")
+
+// errConnLost is returned by requestTunnel when the underlying tunnelConn's serveInbound loop
+// has ended (socket closed, heartbeat failure, ...) while a request was in flight. A Dial-ed
+// Client uses this to distinguish "the tunnel died, retry once reconnected" from a hard error.
+var errConnLost = errors.New("knx: tunnel connection lost")
+
 // tunnelConn is a handle for a tunnel connection.
 type tunnelConn struct {
 	sock    Socket
@@ -67,6 +101,33 @@ type tunnelConn struct {
 	ack       chan *proto.TunnelRes
 
 	inbound chan *cemi.CEMI
+
+	// gracefulShutdownC is closed once a graceful shutdown has been initiated. It is only ever
+	// closed by initiateShutdown, via shutdownOnce, so that repeated calls don't panic.
+	gracefulShutdownC chan struct{}
+	shutdownOnce      sync.Once
+
+	// discAckMu guards discAckC, which Shutdown installs so that serveInbound can hand it the
+	// matching DiscRes once the gateway confirms the disconnect.
+	discAckMu sync.Mutex
+	discAckC  chan struct{}
+}
+
+// initiateShutdown marks the connection as shutting down. It is safe to call more than once.
+func (conn *tunnelConn) initiateShutdown() {
+	conn.shutdownOnce.Do(func() {
+		close(conn.gracefulShutdownC)
+	})
+}
+
+// isShuttingDown reports whether a graceful shutdown has been initiated.
+func (conn *tunnelConn) isShuttingDown() bool {
+	select {
+	case <-conn.gracefulShutdownC:
+		return true
+	default:
+		return false
+	}
 }
 
 // newTunnelConn repeatedly sends a connection request through the socket until the provided context gets
@@ -77,7 +138,12 @@ func newTunnelConn(
 	sock Socket,
 	config ClientConfig,
 ) (*tunnelConn, error) {
-	req := &proto.ConnReq{}
+	req := &proto.ConnReq{
+		CRI: proto.CRI{
+			ConnectionType: proto.TunnelConnection,
+			KNXLayer:       config.ConnType,
+		},
+	}
 
 	// Send the initial request.
 	err := sock.Send(req)
@@ -115,13 +181,14 @@ func newTunnelConn(
 				// Conection has been established.
 				case proto.ConnResOk:
 					return &tunnelConn{
-						sock:      sock,
-						config:    config,
-						channel:   res.Channel,
-						seqMu:     &sync.Mutex{},
-						seqNumber: 0,
-						ack:       make(chan *proto.TunnelRes),
-						inbound:   make(chan *cemi.CEMI),
+						sock:              sock,
+						config:            config,
+						channel:           res.Channel,
+						seqMu:             &sync.Mutex{},
+						seqNumber:         0,
+						ack:               make(chan *proto.TunnelRes),
+						inbound:           make(chan *cemi.CEMI),
+						gracefulShutdownC: make(chan struct{}),
 					}, nil
 
 				// The gateway is busy, but we don't stop yet.
@@ -184,6 +251,12 @@ func (conn *tunnelConn) requestTunnel(
 	ctx context.Context,
 	data cemi.CEMI,
 ) error {
+	// Once a graceful shutdown has started, the gateway is about to tear down the channel; don't
+	// bother starting a new resend cycle that can only time out.
+	if conn.isShuttingDown() {
+		return ErrShuttingDown
+	}
+
 	// Sequence numbers cannot be reused, therefore we must protect against that.
 	conn.seqMu.Lock()
 	defer conn.seqMu.Unlock()
@@ -220,7 +293,7 @@ func (conn *tunnelConn) requestTunnel(
 		// Received a tunnel response.
 		case res, open := <-conn.ack:
 			if !open {
-				return errors.New("Ack channel is closed")
+				return errConnLost
 			}
 
 			// Ignore mismatching sequence numbers.
@@ -294,6 +367,14 @@ func (conn *tunnelConn) handleDisconnectResponse(
 		return errors.New("Invalid communication channel in disconnect response")
 	}
 
+	// Hand the confirmation to a waiting Shutdown call, if there is one.
+	conn.discAckMu.Lock()
+	if conn.discAckC != nil {
+		close(conn.discAckC)
+		conn.discAckC = nil
+	}
+	conn.discAckMu.Unlock()
+
 	return nil
 }
 
@@ -401,6 +482,12 @@ func (conn *tunnelConn) serveInbound(
 
 		// There were no incoming packets for some time.
 		case <-time.After(conn.config.HeartbeatDelay):
+			// A graceful shutdown is already tearing down the channel; there's nothing left
+			// to keep alive.
+			if conn.isShuttingDown() {
+				continue
+			}
+
 			go conn.performHeartbeat(ctx, heartbeat, timeout)
 
 		// A message has been received or the channel is closed.
@@ -455,7 +542,21 @@ type Client struct {
 	ctx    context.Context
 	cancel context.CancelFunc
 
-	conn *tunnelConn
+	// mu guards conn and connGenC. Both are only ever reassigned by the reconnect supervisor
+	// started by Dial; a plain Connect-ed client never touches them after construction.
+	mu       sync.Mutex
+	conn     *tunnelConn
+	connGenC chan struct{}
+
+	config ClientConfig
+
+	// supervisor is true for clients obtained through Dial. Such clients transparently
+	// reconnect instead of surfacing a dead tunnel to the caller.
+	supervisor  bool
+	gatewayAddr string
+
+	inbound chan *cemi.CEMI
+	state   chan ConnState
 }
 
 // Connect establishes a connection with a gateway. You can pass a zero initialized ClientConfig;
@@ -483,38 +584,117 @@ func Connect(gatewayAddr string, config ClientConfig) (*Client, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &Client{
-		ctx,
-		cancel,
-		conn,
+		ctx:    ctx,
+		cancel: cancel,
+		conn:   conn,
+		config: config,
 	}, nil
 }
 
-// Serve starts the internal connection server, which is needed to process incoming packets.
+// Serve starts the internal connection server, which is needed to process incoming packets. For
+// a Dial-ed client, Serve also owns the reconnect supervisor and will not return until Shutdown
+// or Close is called, or the context a reconnect attempt depends on is otherwise exhausted.
 func (client *Client) Serve() error {
+	if client.supervisor {
+		return client.runSupervisor()
+	}
+
 	return client.conn.serveInbound(client.ctx)
 }
 
-// Close will terminate the connection.
+// Shutdown performs a graceful shutdown of the connection: it notifies the gateway with a
+// DiscReq and waits for the matching DiscRes before releasing local resources, so the gateway
+// frees the channel immediately instead of waiting out the heartbeat timeout. If ctx expires
+// before the gateway responds, Shutdown falls back to Close.
+func (client *Client) Shutdown(ctx context.Context) error {
+	client.mu.Lock()
+	conn := client.conn
+	client.mu.Unlock()
+
+	conn.initiateShutdown()
+
+	ack := make(chan struct{})
+	conn.discAckMu.Lock()
+	conn.discAckC = ack
+	conn.discAckMu.Unlock()
+
+	if err := conn.sock.Send(&proto.DiscReq{Channel: conn.channel}); err != nil {
+		client.Close()
+		return err
+	}
+
+	select {
+	// The gateway confirmed the disconnect; it's now safe to tear down locally.
+	case <-ack:
+		client.cancel()
+		return conn.sock.Close()
+
+	// The gateway never answered in time; fall back to the hard abort.
+	case <-ctx.Done():
+		client.Close()
+		return ctx.Err()
+	}
+}
+
+// Close will terminate the connection immediately, without waiting for the gateway to
+// acknowledge it. Prefer Shutdown when a graceful disconnect is possible.
 func (client *Client) Close() {
+	client.mu.Lock()
+	conn := client.conn
+	client.mu.Unlock()
+
+	conn.initiateShutdown()
 	client.cancel()
+	conn.sock.Close()
 }
 
-// Inbound retrieves the channel which transmits incoming data.
+// Inbound retrieves the channel which transmits incoming data. For a Dial-ed client this
+// channel spans reconnects: messages keep arriving on the same channel across a reconnect, and
+// none seen before a disconnect are replayed afterwards.
 func (client *Client) Inbound() <-chan *cemi.CEMI {
+	if client.supervisor {
+		return client.inbound
+	}
+
 	return client.conn.inbound
 }
 
-// Send relays a tunnel request to the gateway with the given contents.
+// Send relays a tunnel request to the gateway with the given contents. On a Dial-ed client,
+// Send blocks across a reconnect (up to ResponseTimeout) instead of failing as soon as the
+// underlying tunnel dies.
 func (client *Client) Send(data cemi.CEMI) error {
-	// Prepare a context, so that we won't wait forever for a tunnel response.
-	ctx, cancel := context.WithTimeout(client.ctx, client.conn.config.ResponseTimeout)
-	defer cancel()
-
-	// Send the tunnel reqest.
-	err := client.conn.requestTunnel(ctx, data)
-	if err != nil {
-		return err
+	if client.config.ConnType == proto.ConnTypeBusmonitor {
+		return ErrBusmonitorReadOnly
 	}
 
-	return nil
+	deadline := time.Now().Add(client.config.ResponseTimeout)
+
+	for {
+		client.mu.Lock()
+		conn := client.conn
+		gen := client.connGenC
+		client.mu.Unlock()
+
+		// Prepare a context, so that we won't wait forever for a tunnel response.
+		ctx, cancel := context.WithDeadline(client.ctx, deadline)
+		err := conn.requestTunnel(ctx, data)
+		cancel()
+
+		if err == nil || !client.supervisor || !errors.Is(err, errConnLost) {
+			return err
+		}
+
+		// The tunnel serving this request just died; wait for the supervisor to establish a
+		// new one and retry, as long as there is time left on the deadline.
+		select {
+		case <-gen:
+			continue
+
+		case <-client.ctx.Done():
+			return client.ctx.Err()
+
+		case <-time.After(time.Until(deadline)):
+			return err
+		}
+	}
 }
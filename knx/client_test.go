@@ -0,0 +1,21 @@
+package knx
+
+import "testing"
+
+// TestTunnelConnIsShuttingDown checks the primitive runSupervisor relies on to tell a local
+// Shutdown/Close apart from a gateway-initiated disconnect: isShuttingDown must stay false until
+// initiateShutdown has actually been called, and true (idempotently) after.
+func TestTunnelConnIsShuttingDown(t *testing.T) {
+	conn := &tunnelConn{gracefulShutdownC: make(chan struct{})}
+
+	if conn.isShuttingDown() {
+		t.Fatal("isShuttingDown() = true before initiateShutdown was ever called")
+	}
+
+	conn.initiateShutdown()
+	conn.initiateShutdown() // must not panic on repeated close
+
+	if !conn.isShuttingDown() {
+		t.Fatal("isShuttingDown() = false after initiateShutdown")
+	}
+}
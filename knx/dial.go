@@ -0,0 +1,192 @@
+package knx
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/vapourismo/knx-go/knx/cemi"
+)
+
+// ConnState describes the state of a connection established through Dial.
+type ConnState int
+
+const (
+	// StateConnected indicates that the tunnel is established and ready to use.
+	StateConnected ConnState = iota
+
+	// StateReconnecting indicates that the tunnel was lost and a reconnect is in progress.
+	StateReconnecting
+
+	// StateClosed indicates that the client has shut down and will not reconnect.
+	StateClosed
+)
+
+// Dial establishes a connection with a gateway, like Connect, but the returned Client
+// transparently re-establishes the tunnel whenever it is lost (heartbeat failure, socket
+// closed, gateway-initiated disconnect), using an exponential backoff between attempts. Serve
+// will not return until Shutdown or Close is called. State transitions are published on
+// Client.State.
+func Dial(gatewayAddr string, config ClientConfig) (*Client, error) {
+	config = checkClientConfig(config)
+
+	sock, err := NewClientSocket(gatewayAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	connectCtx, cancelConnect := context.WithTimeout(context.Background(), config.ResponseTimeout)
+	defer cancelConnect()
+
+	conn, err := newTunnelConn(connectCtx, sock, config)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Client{
+		ctx:         ctx,
+		cancel:      cancel,
+		conn:        conn,
+		connGenC:    make(chan struct{}),
+		config:      config,
+		supervisor:  true,
+		gatewayAddr: gatewayAddr,
+		inbound:     make(chan *cemi.CEMI),
+		state:       make(chan ConnState, 1),
+	}, nil
+}
+
+// State retrieves the channel on which connection state transitions are published. Only
+// clients obtained through Dial publish on it; for a Connect-ed client it is never sent to.
+func (client *Client) State() <-chan ConnState {
+	return client.state
+}
+
+// publishState replaces whatever state is currently buffered with s, so that State() always
+// yields the most recent transition instead of blocking the supervisor on a slow reader.
+func (client *Client) publishState(s ConnState) {
+	select {
+	case <-client.state:
+	default:
+	}
+
+	select {
+	case client.state <- s:
+	default:
+	}
+}
+
+// reconnect dials a fresh socket and tunnel connection to the gateway this client was Dial-ed
+// with.
+func (client *Client) reconnect() (*tunnelConn, error) {
+	sock, err := NewClientSocket(client.gatewayAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(client.ctx, client.config.ResponseTimeout)
+	defer cancel()
+
+	return newTunnelConn(ctx, sock, client.config)
+}
+
+// forwardInbound relays conn's inbound messages onto the client's long-lived inbound channel
+// until conn's own channel is closed, i.e. until that generation's serveInbound returns.
+func (client *Client) forwardInbound(conn *tunnelConn) {
+	for msg := range conn.inbound {
+		select {
+		case client.inbound <- msg:
+		case <-client.ctx.Done():
+			return
+		}
+	}
+}
+
+// runSupervisor serves the current connection and, as long as the client hasn't been shut down,
+// transparently replaces it with a freshly dialed one whenever it is lost. The backoff between
+// reconnect attempts doubles on each failure up to config.MaxBackoff, with jitter to avoid
+// reconnect storms against the same gateway.
+func (client *Client) runSupervisor() error {
+	client.mu.Lock()
+	conn := client.conn
+	client.mu.Unlock()
+
+	go client.forwardInbound(conn)
+	client.publishState(StateConnected)
+
+	backoff := client.config.ResendInterval
+
+	for {
+		err := conn.serveInbound(client.ctx)
+
+		if client.ctx.Err() != nil {
+			client.publishState(StateClosed)
+			return client.ctx.Err()
+		}
+
+		if err == nil && conn.isShuttingDown() {
+			// Our own Shutdown/Close initiated this: the handshake (or hard abort) already
+			// happened, and client.ctx is about to be canceled. Nothing to reconnect.
+			client.publishState(StateClosed)
+			return nil
+		}
+
+		if err == nil {
+			// serveInbound returned nil without us having asked for a shutdown, which only
+			// happens on a gateway-initiated DiscReq/DiscRes. Treat it like any other lost
+			// tunnel and reconnect.
+			err = errConnLost
+		}
+
+		log(client, "conn", "Tunnel connection lost, reconnecting: %v", err)
+		client.publishState(StateReconnecting)
+
+		for {
+			jitter := time.Duration(rand.Int63n(int64(backoff) + 1))
+
+			select {
+			case <-client.ctx.Done():
+				client.publishState(StateClosed)
+				return client.ctx.Err()
+
+			case <-time.After(backoff/2 + jitter/2):
+			}
+
+			newConn, dialErr := client.reconnect()
+			if dialErr != nil {
+				log(client, "conn", "Reconnect attempt failed: %v", dialErr)
+
+				backoff *= 2
+				if backoff > client.config.MaxBackoff {
+					backoff = client.config.MaxBackoff
+				}
+
+				continue
+			}
+
+			oldConn := conn
+
+			client.mu.Lock()
+			conn = newConn
+			client.conn = newConn
+			gen := client.connGenC
+			client.connGenC = make(chan struct{})
+			client.mu.Unlock()
+			close(gen)
+
+			// oldConn's serveInbound has already returned (that's why we're here); its socket
+			// and reader goroutine are now unreachable from anywhere else and must be closed
+			// explicitly, or they leak for the rest of the process's life.
+			oldConn.sock.Close()
+
+			go client.forwardInbound(conn)
+
+			backoff = client.config.ResendInterval
+			client.publishState(StateConnected)
+
+			break
+		}
+	}
+}
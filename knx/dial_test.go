@@ -0,0 +1,29 @@
+package knx
+
+import "testing"
+
+// TestClientPublishStateKeepsOnlyLatest checks that publishState never blocks on a slow or
+// absent State() reader, and that State() always yields the most recent transition rather than
+// the oldest one still sitting in the buffer.
+func TestClientPublishStateKeepsOnlyLatest(t *testing.T) {
+	client := &Client{state: make(chan ConnState, 1)}
+
+	client.publishState(StateConnected)
+	client.publishState(StateReconnecting)
+	client.publishState(StateClosed)
+
+	select {
+	case s := <-client.state:
+		if s != StateClosed {
+			t.Fatalf("State() = %v, want most recent StateClosed", s)
+		}
+	default:
+		t.Fatal("State() channel is empty, want the latest published state")
+	}
+
+	select {
+	case s := <-client.state:
+		t.Fatalf("State() yielded a second value %v, want only the latest to survive", s)
+	default:
+	}
+}
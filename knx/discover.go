@@ -0,0 +1,159 @@
+package knx
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/vapourismo/knx-go/knx/proto"
+)
+
+// ServiceFamilyTunneling is the service family ID advertised by gateways that support KNXnet/IP
+// Tunneling, as used in GatewayInfo.SupportedServices.
+const ServiceFamilyTunneling uint8 = 0x04
+
+var defaultDiscoverTimeout = 3 * time.Second
+
+// DiscoverOptions configures Discover.
+type DiscoverOptions struct {
+	// Timeout bounds how long Discover collects SEARCH_RESPONSE frames for. It is overridden by
+	// an earlier deadline on the ctx passed to Discover. A value <= 0 will result in the use of
+	// a default value.
+	Timeout time.Duration
+
+	// Describe, if true, makes Discover follow up each SEARCH_RESPONSE with a unicast
+	// DESCRIPTION_REQUEST to fully populate GatewayInfo.
+	Describe bool
+}
+
+// GatewayInfo describes a KNXnet/IP gateway found via Discover.
+type GatewayInfo struct {
+	Name              string
+	MACAddr           net.HardwareAddr
+	IndividualAddr    uint16
+	SupportedServices map[uint8]uint8
+	ControlEndpoint   proto.HostInfo
+	MediumType        uint8
+}
+
+// Discover sends a SEARCH_REQUEST to the KNXnet/IP Routing multicast group and collects
+// SEARCH_RESPONSE frames for opts.Timeout, or until ctx is done, whichever comes first. If
+// opts.Describe is set, each responder is additionally queried with a unicast
+// DESCRIPTION_REQUEST to fill in the remaining GatewayInfo fields.
+func Discover(ctx context.Context, opts DiscoverOptions) ([]GatewayInfo, error) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultDiscoverTimeout
+	}
+
+	sock, err := NewMulticastSocket(DefaultMulticastAddr)
+	if err != nil {
+		return nil, err
+	}
+	defer sock.Close()
+
+	searchCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	if err := sock.Send(&proto.SearchReq{}); err != nil {
+		return nil, err
+	}
+
+	var gateways []GatewayInfo
+
+	for {
+		select {
+		// The collection window has elapsed.
+		case <-searchCtx.Done():
+			if opts.Describe {
+				// searchCtx is done here by design -- that's what ends the search window -- so
+				// it can't bound the describe round trips. Each one gets its own budget,
+				// independent of both searchCtx and ctx.
+				for i := range gateways {
+					describeGateway(context.Background(), &gateways[i])
+				}
+			}
+
+			return gateways, nil
+
+		// A message has been received or the channel has been closed.
+		case msg, open := <-sock.Inbound():
+			if !open {
+				return gateways, errors.New("knx: discovery socket closed unexpectedly")
+			}
+
+			if res, ok := msg.(*proto.SearchRes); ok {
+				gateways = append(gateways, GatewayInfo{
+					Name:              res.Device.Name,
+					MACAddr:           net.HardwareAddr(res.Device.MACAddr[:]),
+					IndividualAddr:    res.Device.IndividualAddr,
+					SupportedServices: res.Services.Families,
+					ControlEndpoint:   res.Control,
+					MediumType:        res.Device.MediumType,
+				})
+			}
+		}
+	}
+}
+
+// describeGateway follows up on a SEARCH_RESPONSE with a unicast DESCRIPTION_REQUEST, to fill in
+// the fields a SEARCH_RESPONSE doesn't carry. Errors are not fatal to Discover as a whole: info
+// simply keeps whatever it already learned from the SEARCH_RESPONSE.
+func describeGateway(ctx context.Context, info *GatewayInfo) {
+	sock, err := NewClientSocket(info.ControlEndpoint.String())
+	if err != nil {
+		return
+	}
+	defer sock.Close()
+
+	reqCtx, cancel := context.WithTimeout(ctx, defaultResponseTimeout)
+	defer cancel()
+
+	if err := sock.Send(&proto.DescrReq{}); err != nil {
+		return
+	}
+
+	for {
+		select {
+		case <-reqCtx.Done():
+			return
+
+		case msg, open := <-sock.Inbound():
+			if !open {
+				return
+			}
+
+			if res, ok := msg.(*proto.DescrRes); ok {
+				info.Name = res.Device.Name
+				info.MACAddr = net.HardwareAddr(res.Device.MACAddr[:])
+				info.IndividualAddr = res.Device.IndividualAddr
+				info.SupportedServices = res.Services.Families
+				info.MediumType = res.Device.MediumType
+				return
+			}
+		}
+	}
+}
+
+// ConnectAuto discovers gateways on the LAN and dials the first one that advertises the
+// tunneling service family, for zero-config deployments where the gateway address isn't known
+// ahead of time.
+func ConnectAuto(config ClientConfig) (*Client, error) {
+	config = checkClientConfig(config)
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultDiscoverTimeout)
+	defer cancel()
+
+	gateways, err := Discover(ctx, DiscoverOptions{Timeout: defaultDiscoverTimeout, Describe: true})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, gw := range gateways {
+		if _, ok := gw.SupportedServices[ServiceFamilyTunneling]; ok {
+			return Connect(gw.ControlEndpoint.String(), config)
+		}
+	}
+
+	return nil, errors.New("knx: no gateway advertising the tunneling service family was found")
+}
@@ -0,0 +1,39 @@
+package proto
+
+import "fmt"
+
+// ConnReq is sent to open a new tunnel connection with a gateway, identifying the control and
+// data endpoints to use and, via CRI, which kind of tunnel is being requested.
+type ConnReq struct {
+	Control HostInfo
+	Data    HostInfo
+	CRI     CRI
+}
+
+// Pack assembles the CONNECT_REQUEST body.
+func (req *ConnReq) Pack(buffer []byte) []byte {
+	buffer = req.Control.Pack(buffer)
+	buffer = req.Data.Pack(buffer)
+	return req.CRI.Pack(buffer)
+}
+
+// Unpack parses a CONNECT_REQUEST body and returns the number of bytes it consumed from data.
+func (req *ConnReq) Unpack(data []byte) (uint, error) {
+	n, err := req.Control.Unpack(data)
+	if err != nil {
+		return 0, err
+	}
+
+	m, err := req.Data.Unpack(data[n:])
+	if err != nil {
+		return 0, err
+	}
+	n += m
+
+	m, err = req.CRI.Unpack(data[n:])
+	if err != nil {
+		return 0, fmt.Errorf("knx: malformed CRI in CONNECT_REQUEST: %w", err)
+	}
+
+	return n + m, nil
+}
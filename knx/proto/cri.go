@@ -0,0 +1,57 @@
+package proto
+
+import "errors"
+
+// criLen is the fixed wire length of a CRI structure, including its own structure length byte.
+const criLen = 4
+
+// TunnelConnection is the CRI connection type code for a tunneling connection. It is the only
+// connection type ConnReq currently supports; KNX also defines others (e.g. device management)
+// that this module does not implement.
+const TunnelConnection uint8 = 0x04
+
+// ConnType identifies which kind of tunnel is being requested in a CRI: the standard link-layer
+// tunnel, a raw cEMI tunnel, or a receive-only busmonitor tunnel.
+type ConnType uint8
+
+const (
+	// ConnTypeTunnel requests a standard TUNNEL_LINKLAYER connection, the default used by
+	// Connect.
+	ConnTypeTunnel ConnType = 0x02
+
+	// ConnTypeTunnelRaw requests a TUNNEL_RAW connection: cEMI frames are exchanged verbatim,
+	// without link-layer framing or validation.
+	ConnTypeTunnelRaw ConnType = 0x04
+
+	// ConnTypeBusmonitor requests a TUNNEL_BUSMONITOR connection: every frame seen on the bus is
+	// delivered, including malformed and NACKed ones, but the connection is receive-only.
+	ConnTypeBusmonitor ConnType = 0x80
+)
+
+// CRI is the Connection Request Information block of a ConnReq: it tells the gateway which kind
+// of connection, and which tunnel layer, is being requested.
+type CRI struct {
+	ConnectionType uint8
+	KNXLayer       ConnType
+}
+
+// Pack assembles the CRI structure. The fourth byte is reserved and always zero.
+func (cri *CRI) Pack(buffer []byte) []byte {
+	return append(buffer, criLen, cri.ConnectionType, uint8(cri.KNXLayer), 0x00)
+}
+
+// Unpack parses a CRI structure and returns the number of bytes it consumed from data.
+func (cri *CRI) Unpack(data []byte) (uint, error) {
+	if len(data) < criLen {
+		return 0, errors.New("knx: CRI is too short")
+	}
+
+	if data[0] != criLen {
+		return 0, errors.New("knx: malformed CRI")
+	}
+
+	cri.ConnectionType = data[1]
+	cri.KNXLayer = ConnType(data[2])
+
+	return criLen, nil
+}
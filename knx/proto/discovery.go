@@ -0,0 +1,233 @@
+package proto
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"sort"
+)
+
+// dibTypeDeviceInfo and dibTypeSuppSvcFamilies are the description type codes that identify a
+// DIB's layout, per the KNXnet/IP Core spec.
+const (
+	dibTypeDeviceInfo      = 0x01
+	dibTypeSuppSvcFamilies = 0x02
+)
+
+// deviceInfoDIBLen is the fixed wire length of a DEVICE_INFO DIB, including its own structure
+// length and type code bytes.
+const deviceInfoDIBLen = 54
+
+// deviceInfoNameLen is the fixed, zero-padded width of the friendly name field within a
+// DEVICE_INFO DIB.
+const deviceInfoNameLen = 30
+
+// DeviceInfoDIB carries the DEVICE_INFO description information block of a SEARCH_RESPONSE or
+// DESCRIPTION_RESPONSE: the gateway's identity on the KNX bus.
+type DeviceInfoDIB struct {
+	MediumType       uint8
+	DeviceStatus     uint8
+	IndividualAddr   uint16
+	ProjectInstallID uint16
+	SerialNumber     [6]byte
+	MulticastAddr    [4]byte
+	MACAddr          [6]byte
+	Name             string
+}
+
+// Pack assembles the DEVICE_INFO DIB. It is a fixed 54-byte structure; Name is right-padded with
+// zero bytes to deviceInfoNameLen and truncated if it doesn't fit.
+func (dib *DeviceInfoDIB) Pack(buffer []byte) []byte {
+	buffer = append(buffer, deviceInfoDIBLen, dibTypeDeviceInfo, dib.MediumType, dib.DeviceStatus)
+
+	var word [2]byte
+
+	binary.BigEndian.PutUint16(word[:], dib.IndividualAddr)
+	buffer = append(buffer, word[:]...)
+
+	binary.BigEndian.PutUint16(word[:], dib.ProjectInstallID)
+	buffer = append(buffer, word[:]...)
+
+	buffer = append(buffer, dib.SerialNumber[:]...)
+	buffer = append(buffer, dib.MulticastAddr[:]...)
+	buffer = append(buffer, dib.MACAddr[:]...)
+
+	name := make([]byte, deviceInfoNameLen)
+	copy(name, dib.Name)
+	buffer = append(buffer, name...)
+
+	return buffer
+}
+
+// Unpack parses a DEVICE_INFO DIB and returns the number of bytes it consumed from data.
+func (dib *DeviceInfoDIB) Unpack(data []byte) (uint, error) {
+	if len(data) < deviceInfoDIBLen {
+		return 0, errors.New("knx: DEVICE_INFO DIB is too short")
+	}
+
+	if data[0] != deviceInfoDIBLen || data[1] != dibTypeDeviceInfo {
+		return 0, errors.New("knx: not a DEVICE_INFO DIB")
+	}
+
+	dib.MediumType = data[2]
+	dib.DeviceStatus = data[3]
+	dib.IndividualAddr = binary.BigEndian.Uint16(data[4:6])
+	dib.ProjectInstallID = binary.BigEndian.Uint16(data[6:8])
+	copy(dib.SerialNumber[:], data[8:14])
+	copy(dib.MulticastAddr[:], data[14:18])
+	copy(dib.MACAddr[:], data[18:24])
+
+	name := data[24:deviceInfoDIBLen]
+	if end := bytes.IndexByte(name, 0); end >= 0 {
+		name = name[:end]
+	}
+	dib.Name = string(name)
+
+	return deviceInfoDIBLen, nil
+}
+
+// SupportedServiceFamiliesDIB carries the SUPP_SVC_FAMILIES description information block: which
+// KNXnet/IP service families (tunneling, routing, ...) and versions the gateway supports, keyed
+// by service family ID. Unlike DeviceInfoDIB, its length varies with the number of families.
+type SupportedServiceFamiliesDIB struct {
+	Families map[uint8]uint8
+}
+
+// Pack assembles the SUPP_SVC_FAMILIES DIB: a structure length and type code, followed by one
+// (family ID, version) pair per entry, in ascending family ID order for a deterministic wire
+// encoding.
+func (dib *SupportedServiceFamiliesDIB) Pack(buffer []byte) []byte {
+	length := 2 + 2*len(dib.Families)
+	buffer = append(buffer, uint8(length), dibTypeSuppSvcFamilies)
+
+	ids := make([]int, 0, len(dib.Families))
+	for id := range dib.Families {
+		ids = append(ids, int(id))
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		buffer = append(buffer, uint8(id), dib.Families[uint8(id)])
+	}
+
+	return buffer
+}
+
+// Unpack parses a SUPP_SVC_FAMILIES DIB and returns the number of bytes it consumed from data.
+func (dib *SupportedServiceFamiliesDIB) Unpack(data []byte) (uint, error) {
+	if len(data) < 2 {
+		return 0, errors.New("knx: SUPP_SVC_FAMILIES DIB is too short")
+	}
+
+	length := uint(data[0])
+	if data[1] != dibTypeSuppSvcFamilies {
+		return 0, errors.New("knx: not a SUPP_SVC_FAMILIES DIB")
+	}
+
+	if uint(len(data)) < length {
+		return 0, errors.New("knx: SUPP_SVC_FAMILIES DIB is truncated")
+	}
+
+	dib.Families = make(map[uint8]uint8)
+	for i := uint(2); i+1 < length; i += 2 {
+		dib.Families[data[i]] = data[i+1]
+	}
+
+	return length, nil
+}
+
+// SearchReq is broadcast to the Routing multicast group to ask any listening gateway to announce
+// itself via a SearchRes.
+type SearchReq struct {
+	Control HostInfo
+}
+
+// Pack assembles the SEARCH_REQUEST body.
+func (req *SearchReq) Pack(buffer []byte) []byte {
+	return req.Control.Pack(buffer)
+}
+
+// Unpack parses a SEARCH_REQUEST body and returns the number of bytes it consumed from data.
+func (req *SearchReq) Unpack(data []byte) (uint, error) {
+	return req.Control.Unpack(data)
+}
+
+// SearchRes is a gateway's answer to a SearchReq, announcing its control endpoint, identity and
+// supported service families.
+type SearchRes struct {
+	Control  HostInfo
+	Device   DeviceInfoDIB
+	Services SupportedServiceFamiliesDIB
+}
+
+// Pack assembles the SEARCH_RESPONSE body.
+func (res *SearchRes) Pack(buffer []byte) []byte {
+	buffer = res.Control.Pack(buffer)
+	buffer = res.Device.Pack(buffer)
+	return res.Services.Pack(buffer)
+}
+
+// Unpack parses a SEARCH_RESPONSE body and returns the number of bytes it consumed from data.
+func (res *SearchRes) Unpack(data []byte) (uint, error) {
+	n, err := res.Control.Unpack(data)
+	if err != nil {
+		return 0, err
+	}
+
+	m, err := res.Device.Unpack(data[n:])
+	if err != nil {
+		return 0, err
+	}
+	n += m
+
+	m, err = res.Services.Unpack(data[n:])
+	if err != nil {
+		return 0, err
+	}
+
+	return n + m, nil
+}
+
+// DescrReq asks a specific gateway, over unicast, for the same description a SearchRes carries.
+type DescrReq struct {
+	Control HostInfo
+}
+
+// Pack assembles the DESCRIPTION_REQUEST body.
+func (req *DescrReq) Pack(buffer []byte) []byte {
+	return req.Control.Pack(buffer)
+}
+
+// Unpack parses a DESCRIPTION_REQUEST body and returns the number of bytes it consumed from
+// data.
+func (req *DescrReq) Unpack(data []byte) (uint, error) {
+	return req.Control.Unpack(data)
+}
+
+// DescrRes answers a DescrReq.
+type DescrRes struct {
+	Device   DeviceInfoDIB
+	Services SupportedServiceFamiliesDIB
+}
+
+// Pack assembles the DESCRIPTION_RESPONSE body.
+func (res *DescrRes) Pack(buffer []byte) []byte {
+	buffer = res.Device.Pack(buffer)
+	return res.Services.Pack(buffer)
+}
+
+// Unpack parses a DESCRIPTION_RESPONSE body and returns the number of bytes it consumed from
+// data.
+func (res *DescrRes) Unpack(data []byte) (uint, error) {
+	n, err := res.Device.Unpack(data)
+	if err != nil {
+		return 0, err
+	}
+
+	m, err := res.Services.Unpack(data[n:])
+	if err != nil {
+		return 0, err
+	}
+
+	return n + m, nil
+}
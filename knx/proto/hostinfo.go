@@ -0,0 +1,55 @@
+package proto
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// hostInfoLen is the fixed wire length of a HostInfo structure, including its own structure
+// length byte.
+const hostInfoLen = 8
+
+// hostProtocolUDP is the only host protocol code this package supports, per KNXnet/IP's de facto
+// restriction of Tunneling/Routing to UDP.
+const hostProtocolUDP uint8 = 0x01
+
+// HostInfo identifies a UDP endpoint (IP address and port) within a KNXnet/IP frame, e.g. the
+// control endpoint a SEARCH_RESPONSE should be sent back to.
+type HostInfo struct {
+	Protocol uint8
+	Address  [4]byte
+	Port     uint16
+}
+
+// Pack assembles a HostInfo structure.
+func (info *HostInfo) Pack(buffer []byte) []byte {
+	protocol := info.Protocol
+	if protocol == 0 {
+		protocol = hostProtocolUDP
+	}
+
+	buffer = append(buffer, hostInfoLen, protocol)
+	buffer = append(buffer, info.Address[:]...)
+
+	var port [2]byte
+	binary.BigEndian.PutUint16(port[:], info.Port)
+
+	return append(buffer, port[:]...)
+}
+
+// Unpack parses a HostInfo structure and returns the number of bytes it consumed from data.
+func (info *HostInfo) Unpack(data []byte) (uint, error) {
+	if len(data) < hostInfoLen {
+		return 0, errors.New("knx: HostInfo is too short")
+	}
+
+	if data[0] != hostInfoLen {
+		return 0, errors.New("knx: malformed HostInfo")
+	}
+
+	info.Protocol = data[1]
+	copy(info.Address[:], data[2:6])
+	info.Port = binary.BigEndian.Uint16(data[6:8])
+
+	return hostInfoLen, nil
+}
@@ -0,0 +1,252 @@
+package proto
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// headerLength and protocolVersion are fixed by the KNXnet/IP Core spec: every frame starts with
+// a 6-byte header whose first two bytes are always these values.
+const (
+	headerLength    = 6
+	protocolVersion = 0x10
+)
+
+// Service identifiers from the KNXnet/IP header that this package knows how to decode/encode.
+// Not every service defined by the spec has a case in unpackBody/packBody yet; unsupported ones
+// simply fail to decode/encode until a type and Pack/Unpack are added for them.
+const (
+	serviceSearchReq      uint16 = 0x0201
+	serviceSearchRes      uint16 = 0x0202
+	serviceDescrReq       uint16 = 0x0203
+	serviceDescrRes       uint16 = 0x0204
+	serviceConnReq        uint16 = 0x0205
+	serviceRoutingInd     uint16 = 0x0530
+	serviceRoutingLostMsg uint16 = 0x0531
+	serviceRoutingBusy    uint16 = 0x0532
+)
+
+// maxFrameLength sizes the buffer ReadFrom and ReadFromAddr read each datagram into. It is large
+// enough to hold any KNXnet/IP frame this package decodes: a frame is decoded from a single
+// datagram, never assembled from several reads, so the buffer must hold the whole datagram.
+const maxFrameLength = 4096
+
+// FrameError indicates that a single datagram could not be decoded as a KNXnet/IP frame (bad
+// header, unrecognized service identifier, truncated body). Unlike an error from the underlying
+// socket, it doesn't mean the socket is dead: the caller should discard the datagram and keep
+// reading.
+type FrameError struct {
+	Err error
+}
+
+func (e *FrameError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FrameError) Unwrap() error {
+	return e.Err
+}
+
+// packHeader assembles the 6-byte KNXnet/IP header for a frame carrying a body of the given
+// length under the given service identifier.
+func packHeader(serviceID uint16, bodyLength int) []byte {
+	header := make([]byte, headerLength)
+
+	header[0] = headerLength
+	header[1] = protocolVersion
+	binary.BigEndian.PutUint16(header[2:4], serviceID)
+	binary.BigEndian.PutUint16(header[4:6], uint16(headerLength+bodyLength))
+
+	return header
+}
+
+// encodeFrame assembles the full wire representation (header and body) of payload.
+func encodeFrame(payload interface{}) ([]byte, error) {
+	serviceID, body, err := packBody(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(packHeader(serviceID, len(body)), body...), nil
+}
+
+// decodeFrame parses a single datagram, header and body, into the message type registered for
+// its service identifier.
+func decodeFrame(data []byte) (interface{}, error) {
+	if len(data) < headerLength {
+		return nil, &FrameError{Err: errors.New("knx: frame is shorter than the KNXnet/IP header")}
+	}
+
+	if data[0] != headerLength {
+		return nil, &FrameError{Err: errors.New("knx: unexpected header length")}
+	}
+
+	if data[1] != protocolVersion {
+		return nil, &FrameError{Err: fmt.Errorf("knx: unsupported protocol version %#x", data[1])}
+	}
+
+	serviceID := binary.BigEndian.Uint16(data[2:4])
+	totalLength := binary.BigEndian.Uint16(data[4:6])
+
+	if int(totalLength) != len(data) {
+		return nil, &FrameError{Err: errors.New("knx: frame length does not match header")}
+	}
+
+	msg, err := unpackBody(serviceID, data[headerLength:])
+	if err != nil {
+		return nil, &FrameError{Err: err}
+	}
+
+	return msg, nil
+}
+
+// packBody dispatches payload to its Pack method and returns the service identifier it belongs
+// under. Every message type this package can put on the wire needs a case here.
+func packBody(payload interface{}) (uint16, []byte, error) {
+	switch msg := payload.(type) {
+	case *SearchReq:
+		return serviceSearchReq, msg.Pack(nil), nil
+
+	case *SearchRes:
+		return serviceSearchRes, msg.Pack(nil), nil
+
+	case *DescrReq:
+		return serviceDescrReq, msg.Pack(nil), nil
+
+	case *DescrRes:
+		return serviceDescrRes, msg.Pack(nil), nil
+
+	case *ConnReq:
+		return serviceConnReq, msg.Pack(nil), nil
+
+	case *RoutingInd:
+		return serviceRoutingInd, msg.Pack(nil), nil
+
+	case *RoutingLostMsg:
+		return serviceRoutingLostMsg, msg.Pack(nil), nil
+
+	case *RoutingBusy:
+		return serviceRoutingBusy, msg.Pack(nil), nil
+
+	default:
+		return 0, nil, fmt.Errorf("knx: cannot serialize message of type %T", payload)
+	}
+}
+
+// unpackBody dispatches a frame body to the Unpack method of the message type registered for
+// serviceID. Every service identifier this package can read off the wire needs a case here.
+func unpackBody(serviceID uint16, body []byte) (interface{}, error) {
+	switch serviceID {
+	case serviceSearchReq:
+		msg := &SearchReq{}
+		if _, err := msg.Unpack(body); err != nil {
+			return nil, err
+		}
+		return msg, nil
+
+	case serviceSearchRes:
+		msg := &SearchRes{}
+		if _, err := msg.Unpack(body); err != nil {
+			return nil, err
+		}
+		return msg, nil
+
+	case serviceDescrReq:
+		msg := &DescrReq{}
+		if _, err := msg.Unpack(body); err != nil {
+			return nil, err
+		}
+		return msg, nil
+
+	case serviceDescrRes:
+		msg := &DescrRes{}
+		if _, err := msg.Unpack(body); err != nil {
+			return nil, err
+		}
+		return msg, nil
+
+	case serviceConnReq:
+		msg := &ConnReq{}
+		if _, err := msg.Unpack(body); err != nil {
+			return nil, err
+		}
+		return msg, nil
+
+	case serviceRoutingInd:
+		msg := &RoutingInd{}
+		if _, err := msg.Unpack(body); err != nil {
+			return nil, err
+		}
+		return msg, nil
+
+	case serviceRoutingLostMsg:
+		msg := &RoutingLostMsg{}
+		if _, err := msg.Unpack(body); err != nil {
+			return nil, err
+		}
+		return msg, nil
+
+	case serviceRoutingBusy:
+		msg := &RoutingBusy{}
+		if _, err := msg.Unpack(body); err != nil {
+			return nil, err
+		}
+		return msg, nil
+
+	default:
+		return nil, fmt.Errorf("knx: unsupported service identifier %#04x", serviceID)
+	}
+}
+
+// ReadFrom reads and decodes a single KNXnet/IP frame from conn. A malformed or unrecognized
+// datagram is reported as a *FrameError; the connection itself is still good and the caller may
+// keep reading. Any other error indicates conn itself is no longer usable.
+func ReadFrom(conn *net.UDPConn) (interface{}, error) {
+	buffer := make([]byte, maxFrameLength)
+
+	n, err := conn.Read(buffer)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeFrame(buffer[:n])
+}
+
+// ReadFromAddr is like ReadFrom, but also returns the sender's address, for use on a socket
+// shared by many peers.
+func ReadFromAddr(conn *net.UDPConn) (interface{}, net.Addr, error) {
+	buffer := make([]byte, maxFrameLength)
+
+	n, addr, err := conn.ReadFrom(buffer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	msg, err := decodeFrame(buffer[:n])
+	return msg, addr, err
+}
+
+// WriteTo encodes payload as a KNXnet/IP frame and writes it to conn's connected peer.
+func WriteTo(conn *net.UDPConn, payload interface{}) error {
+	frame, err := encodeFrame(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.Write(frame)
+	return err
+}
+
+// WriteToAddr encodes payload as a KNXnet/IP frame and writes it to addr, for use on an
+// unconnected socket with no single default peer.
+func WriteToAddr(conn *net.UDPConn, payload interface{}, addr net.Addr) error {
+	frame, err := encodeFrame(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.WriteTo(frame, addr)
+	return err
+}
@@ -0,0 +1,107 @@
+package proto
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/vapourismo/knx-go/knx/cemi"
+)
+
+// routingLostMsgLen and routingBusyLen are the fixed wire lengths of a RoutingLostMsg/RoutingBusy
+// body, including their own structure length byte.
+const (
+	routingLostMsgLen = 4
+	routingBusyLen    = 6
+)
+
+// RoutingInd is sent by a KNXnet/IP router to relay a single cEMI frame to every other router on
+// its multicast group. Unlike TunnelReq, there is no acknowledgement or sequence number;
+// delivery over IP multicast is best-effort.
+type RoutingInd struct {
+	Payload cemi.CEMI
+}
+
+// Pack assembles the ROUTING_INDICATION body, which is just the raw cEMI frame.
+func (ind *RoutingInd) Pack(buffer []byte) []byte {
+	return ind.Payload.Pack(buffer)
+}
+
+// Unpack parses a ROUTING_INDICATION body and returns the number of bytes it consumed from data.
+func (ind *RoutingInd) Unpack(data []byte) (uint, error) {
+	return ind.Payload.Unpack(data)
+}
+
+// RoutingLostMsg is sent by a router when its internal queue overflowed and it had to discard
+// one or more frames it should have relayed, so that peers know to expect gaps.
+type RoutingLostMsg struct {
+	DeviceState          uint8
+	NumberOfLostMessages uint16
+}
+
+// Pack assembles the ROUTING_LOST_MESSAGE body.
+func (msg *RoutingLostMsg) Pack(buffer []byte) []byte {
+	buffer = append(buffer, routingLostMsgLen, msg.DeviceState)
+
+	var word [2]byte
+	binary.BigEndian.PutUint16(word[:], msg.NumberOfLostMessages)
+
+	return append(buffer, word[:]...)
+}
+
+// Unpack parses a ROUTING_LOST_MESSAGE body and returns the number of bytes it consumed from
+// data.
+func (msg *RoutingLostMsg) Unpack(data []byte) (uint, error) {
+	if len(data) < routingLostMsgLen {
+		return 0, errors.New("knx: ROUTING_LOST_MESSAGE is too short")
+	}
+
+	if data[0] != routingLostMsgLen {
+		return 0, errors.New("knx: malformed ROUTING_LOST_MESSAGE")
+	}
+
+	msg.DeviceState = data[1]
+	msg.NumberOfLostMessages = binary.BigEndian.Uint16(data[2:4])
+
+	return routingLostMsgLen, nil
+}
+
+// RoutingBusy is sent by a router that can no longer keep up with the multicast group and asks
+// every peer to pause sending for a while. Peers are expected to wait out WaitTime and then
+// resume behind a randomized backoff window, so that they don't all resume in lockstep.
+type RoutingBusy struct {
+	DeviceState        uint8
+	WaitTime           uint16
+	RoutingBusyControl uint16
+}
+
+// Pack assembles the ROUTING_BUSY body.
+func (msg *RoutingBusy) Pack(buffer []byte) []byte {
+	buffer = append(buffer, routingBusyLen, msg.DeviceState)
+
+	var word [2]byte
+
+	binary.BigEndian.PutUint16(word[:], msg.WaitTime)
+	buffer = append(buffer, word[:]...)
+
+	binary.BigEndian.PutUint16(word[:], msg.RoutingBusyControl)
+	buffer = append(buffer, word[:]...)
+
+	return buffer
+}
+
+// Unpack parses a ROUTING_BUSY body and returns the number of bytes it consumed from data.
+func (msg *RoutingBusy) Unpack(data []byte) (uint, error) {
+	if len(data) < routingBusyLen {
+		return 0, errors.New("knx: ROUTING_BUSY is too short")
+	}
+
+	if data[0] != routingBusyLen {
+		return 0, errors.New("knx: malformed ROUTING_BUSY")
+	}
+
+	msg.DeviceState = data[1]
+	msg.WaitTime = binary.BigEndian.Uint16(data[2:4])
+	msg.RoutingBusyControl = binary.BigEndian.Uint16(data[4:6])
+
+	return routingBusyLen, nil
+}
@@ -0,0 +1,172 @@
+package knx
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/vapourismo/knx-go/knx/cemi"
+	"github.com/vapourismo/knx-go/knx/proto"
+)
+
+// DefaultMulticastAddr is the standard KNXnet/IP Routing multicast group and port.
+const DefaultMulticastAddr = "224.0.23.12:3671"
+
+// RouterConfig allows you to configure a Router's behavior.
+type RouterConfig struct {
+	// BusyWaitTimeout caps how long a ROUTING_BUSY pause Send will wait out. If the remaining
+	// pause is longer, Send fails with an error right away, without waiting. A timeout <= 0 will
+	// not be accepted. Instead, the default value will be used.
+	BusyWaitTimeout time.Duration
+}
+
+var defaultBusyWaitTimeout = 5 * time.Second
+
+// DefaultRouterConfig is the default configuration for a Router.
+var DefaultRouterConfig = RouterConfig{BusyWaitTimeout: defaultBusyWaitTimeout}
+
+// checkRouterConfig makes sure that the configuration is actually usable.
+func checkRouterConfig(config RouterConfig) RouterConfig {
+	if config.BusyWaitTimeout <= 0 {
+		config.BusyWaitTimeout = defaultBusyWaitTimeout
+	}
+
+	return config
+}
+
+// Router is a peer on a KNXnet/IP Routing multicast group. Unlike Client, it has no channel, no
+// heartbeat and no connection handshake: frames are simply broadcast to, and received from,
+// every other router on the group.
+type Router struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	sock   Socket
+	config RouterConfig
+
+	inbound chan *cemi.CEMI
+
+	busyMu      sync.Mutex
+	busyUntil   time.Time
+	busyCounter int
+}
+
+// NewRouter joins the given KNXnet/IP Routing multicast group (pass "" for DefaultMulticastAddr)
+// and returns a Router ready to exchange cEMI frames with every other router on it.
+func NewRouter(multicastAddr string, config RouterConfig) (*Router, error) {
+	if multicastAddr == "" {
+		multicastAddr = DefaultMulticastAddr
+	}
+
+	sock, err := NewMulticastSocket(multicastAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	config = checkRouterConfig(config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	router := &Router{
+		ctx:     ctx,
+		cancel:  cancel,
+		sock:    sock,
+		config:  config,
+		inbound: make(chan *cemi.CEMI),
+	}
+
+	go router.serveInbound()
+
+	return router, nil
+}
+
+// serveInbound processes incoming routing frames, mirroring the dispatch style of
+// tunnelConn.serveInbound, but without a channel, sequence numbers or a heartbeat.
+func (router *Router) serveInbound() {
+	defer close(router.inbound)
+
+	for {
+		select {
+		case <-router.ctx.Done():
+			return
+
+		case msg, open := <-router.sock.Inbound():
+			if !open {
+				return
+			}
+
+			switch msg := msg.(type) {
+			case *proto.RoutingInd:
+				select {
+				case <-router.ctx.Done():
+					return
+				case router.inbound <- &msg.Payload:
+				}
+
+			case *proto.RoutingLostMsg:
+				log(router, "router", "Gateway reported %d lost routing messages",
+					msg.NumberOfLostMessages)
+
+			case *proto.RoutingBusy:
+				router.handleBusy(msg)
+			}
+		}
+	}
+}
+
+// handleBusy applies the flow-control backoff described by a received ROUTING_BUSY frame: pause
+// sending for WaitTime, then resume behind a randomized window that grows with the number of
+// ROUTING_BUSY frames seen in a row, so that routers which all back off from the same busy event
+// don't all resume in lockstep.
+func (router *Router) handleBusy(msg *proto.RoutingBusy) {
+	router.busyMu.Lock()
+	defer router.busyMu.Unlock()
+
+	if time.Now().After(router.busyUntil) {
+		router.busyCounter = 0
+	}
+	router.busyCounter++
+
+	randomWindow := time.Duration(router.busyCounter) * 50 * time.Millisecond
+	pause := time.Duration(msg.WaitTime)*time.Millisecond + time.Duration(rand.Int63n(int64(randomWindow)+1))
+
+	if until := time.Now().Add(pause); until.After(router.busyUntil) {
+		router.busyUntil = until
+	}
+}
+
+// Send relays a cEMI frame as a ROUTING_INDICATION to the multicast group. If a ROUTING_BUSY
+// pause is in effect, Send waits it out before sending, unless the remaining pause is longer than
+// RouterConfig.BusyWaitTimeout, in which case Send returns an error without waiting.
+func (router *Router) Send(data cemi.CEMI) error {
+	router.busyMu.Lock()
+	wait := time.Until(router.busyUntil)
+	router.busyMu.Unlock()
+
+	if wait > 0 {
+		if wait > router.config.BusyWaitTimeout {
+			return errors.New("knx: send blocked by routing busy for too long")
+		}
+
+		select {
+		case <-router.ctx.Done():
+			return router.ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+
+	return router.sock.Send(&proto.RoutingInd{Payload: data})
+}
+
+// Inbound retrieves the channel which transmits incoming cEMI frames.
+func (router *Router) Inbound() <-chan *cemi.CEMI {
+	return router.inbound
+}
+
+// Close leaves the multicast group and releases the router's resources.
+func (router *Router) Close() {
+	router.cancel()
+	router.sock.Close()
+}
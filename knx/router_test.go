@@ -0,0 +1,57 @@
+package knx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vapourismo/knx-go/knx/proto"
+)
+
+// TestRouterHandleBusyGrowsWindow checks that consecutive ROUTING_BUSY frames grow the streak
+// counter and never pull busyUntil earlier, so that a burst of busy frames doesn't just collapse
+// to the last one's WaitTime.
+func TestRouterHandleBusyGrowsWindow(t *testing.T) {
+	router := &Router{}
+
+	router.handleBusy(&proto.RoutingBusy{WaitTime: 10})
+	firstUntil := router.busyUntil
+	firstCounter := router.busyCounter
+
+	if firstCounter != 1 {
+		t.Fatalf("busyCounter after first ROUTING_BUSY = %d, want 1", firstCounter)
+	}
+
+	router.handleBusy(&proto.RoutingBusy{WaitTime: 10})
+	secondUntil := router.busyUntil
+	secondCounter := router.busyCounter
+
+	if secondCounter != 2 {
+		t.Fatalf("busyCounter after second ROUTING_BUSY = %d, want 2", secondCounter)
+	}
+
+	if secondUntil.Before(firstUntil) {
+		t.Fatalf("busyUntil went backwards: %v, then %v", firstUntil, secondUntil)
+	}
+}
+
+// TestRouterHandleBusyResetsAfterQuietPeriod checks that the streak counter resets once a prior
+// busy period has fully elapsed, instead of growing without bound across unrelated busy events.
+func TestRouterHandleBusyResetsAfterQuietPeriod(t *testing.T) {
+	router := &Router{}
+
+	router.handleBusy(&proto.RoutingBusy{})
+	router.handleBusy(&proto.RoutingBusy{})
+
+	if router.busyCounter != 2 {
+		t.Fatalf("busyCounter = %d, want 2", router.busyCounter)
+	}
+
+	// Simulate the busy period having fully elapsed.
+	router.busyUntil = time.Now().Add(-time.Millisecond)
+
+	router.handleBusy(&proto.RoutingBusy{})
+
+	if router.busyCounter != 1 {
+		t.Fatalf("busyCounter after quiet period = %d, want 1 (reset)", router.busyCounter)
+	}
+}
@@ -0,0 +1,404 @@
+package knx
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/vapourismo/knx-go/knx/cemi"
+	"github.com/vapourismo/knx-go/knx/proto"
+)
+
+// ConnectionTimeout is how long a connection may stay idle, without a heartbeat, before a
+// Server frees its channel, as mandated by the KNXnet/IP specification.
+const ConnectionTimeout = 120 * time.Second
+
+var defaultMaxConnections = 8
+
+// maxPossibleConnections is the most connections a Server can ever track at once: channel IDs
+// are a single byte and 0 is never assigned.
+const maxPossibleConnections = 255
+
+// mediumTypeIP is the medium type code for KNX IP, as carried in a DEVICE_INFO DIB. A Server
+// only ever speaks KNXnet/IP, so it is not configurable.
+const mediumTypeIP uint8 = 0x02
+
+// ServerConfig allows you to configure a Server's behavior.
+type ServerConfig struct {
+	// MaxConnections caps how many tunnel connections a Server may have open at once; beyond
+	// that, CONNECT_REQUESTs are rejected with ConnResBusy. A value <= 0 will result in the use
+	// of a default value.
+	MaxConnections int
+
+	// HeartbeatTimeout is how long a connection may stay idle before the Server drops it. A
+	// value <= 0 will result in the use of ConnectionTimeout.
+	HeartbeatTimeout time.Duration
+
+	// Name identifies this Server in SEARCH_RESPONSE/DESCRIPTION_RESPONSE frames, so that it can
+	// be told apart from other gateways found via Discover. An empty value is sent as-is.
+	Name string
+
+	// IndividualAddr is the KNX individual address this Server announces itself under. It is
+	// informational only; the Server does not enforce or rewrite it on relayed frames.
+	IndividualAddr uint16
+
+	// MACAddr is the hardware address announced in the DEVICE_INFO DIB. It is purely
+	// informational; a zero value is announced as-is.
+	MACAddr [6]byte
+}
+
+// DefaultServerConfig is the default configuration for a Server.
+var DefaultServerConfig = ServerConfig{
+	MaxConnections:   defaultMaxConnections,
+	HeartbeatTimeout: ConnectionTimeout,
+}
+
+// checkServerConfig makes sure that the configuration is actually usable.
+func checkServerConfig(config ServerConfig) ServerConfig {
+	if config.MaxConnections <= 0 {
+		config.MaxConnections = defaultMaxConnections
+	}
+
+	if config.MaxConnections > maxPossibleConnections {
+		config.MaxConnections = maxPossibleConnections
+	}
+
+	if config.HeartbeatTimeout <= 0 {
+		config.HeartbeatTimeout = ConnectionTimeout
+	}
+
+	return config
+}
+
+// Handler processes a cEMI frame received on a tunnel connection accepted by a Server. conn can
+// be used to push frames back to that specific client.
+type Handler func(conn ServerConn, frame *cemi.CEMI)
+
+// ServerConn is the server-side handle for a single accepted tunnel connection.
+type ServerConn interface {
+	// Channel returns the communication channel ID assigned to this connection.
+	Channel() uint8
+
+	// Send relays a cEMI frame to this client.
+	Send(frame cemi.CEMI) error
+
+	// Close terminates this connection, notifying the client with a DiscReq if possible.
+	Close()
+}
+
+// Server is a KNXnet/IP tunneling gateway: it accepts CONNECT_REQUESTs, allocates channels, runs
+// the inverse of tunnelConn.serveInbound per connection, and relays cEMI frames between its
+// Handler and every connected client.
+type Server struct {
+	config  ServerConfig
+	handler Handler
+
+	sock ServerSocket
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	connsMu     sync.Mutex
+	conns       map[uint8]*serverConn
+	nextChannel uint8
+}
+
+// NewServer binds a Server to bindAddr (typically ":3671") which dispatches incoming cEMI
+// frames to h, using DefaultServerConfig.
+func NewServer(bindAddr string, h Handler) (*Server, error) {
+	return NewServerWithConfig(bindAddr, h, DefaultServerConfig)
+}
+
+// NewServerWithConfig is like NewServer, but lets the caller override ServerConfig.
+func NewServerWithConfig(bindAddr string, h Handler, config ServerConfig) (*Server, error) {
+	sock, err := NewServerSocket(bindAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Server{
+		config:  checkServerConfig(config),
+		handler: h,
+		sock:    sock,
+		ctx:     ctx,
+		cancel:  cancel,
+		conns:   make(map[uint8]*serverConn),
+	}, nil
+}
+
+// Serve processes incoming datagrams until Shutdown is called or the socket closes.
+func (server *Server) Serve() error {
+	for {
+		select {
+		// Termination has been requested.
+		case <-server.ctx.Done():
+			return server.ctx.Err()
+
+		// A message has been received or the socket has been closed.
+		case msg, open := <-server.sock.Inbound():
+			if !open {
+				return errors.New("knx: server socket closed unexpectedly")
+			}
+
+			server.dispatch(msg.Addr, msg.Payload)
+		}
+	}
+}
+
+// Shutdown closes every active connection and releases the server's resources. The given
+// context is currently unused beyond matching the familiar Server.Shutdown(ctx) shape; closing
+// connections and the socket is synchronous.
+func (server *Server) Shutdown(ctx context.Context) error {
+	server.connsMu.Lock()
+	conns := make([]*serverConn, 0, len(server.conns))
+	for _, conn := range server.conns {
+		conns = append(conns, conn)
+	}
+	server.connsMu.Unlock()
+
+	for _, conn := range conns {
+		conn.Close()
+	}
+
+	server.cancel()
+
+	return server.sock.Close()
+}
+
+// dispatch routes a single incoming message, either to the connection it belongs to or, for
+// CONNECT_REQUEST/SEARCH_REQUEST/DESCRIPTION_REQUEST, to the logic that doesn't need one yet.
+// The server socket is unconnected and shared by every client, so a channel-addressed message
+// (TunnelReq/ConnStateReq/DiscReq) is only honored if it actually came from the address that
+// connection was established from; channel IDs are a single byte, so trusting the channel alone
+// would let any sender on the LAN terminate or inject traffic onto someone else's connection.
+func (server *Server) dispatch(addr net.Addr, msg interface{}) {
+	switch msg := msg.(type) {
+	case *proto.SearchReq:
+		server.sock.SendTo(&proto.SearchRes{Device: server.deviceInfo(), Services: server.supportedServices()}, addr)
+
+	case *proto.DescrReq:
+		server.sock.SendTo(&proto.DescrRes{Device: server.deviceInfo(), Services: server.supportedServices()}, addr)
+
+	case *proto.ConnReq:
+		server.handleConnect(addr, msg)
+
+	case *proto.TunnelReq:
+		if conn := server.lookup(msg.Channel); conn != nil && conn.ownsAddr(addr) {
+			server.handleTunnelReq(conn, msg)
+		}
+
+	case *proto.ConnStateReq:
+		if conn := server.lookup(msg.Channel); conn != nil && conn.ownsAddr(addr) {
+			conn.touch()
+			server.sock.SendTo(&proto.ConnStateRes{Channel: msg.Channel, Status: proto.ConnStateNormal}, addr)
+		}
+
+	case *proto.DiscReq:
+		if conn := server.lookup(msg.Channel); conn != nil && conn.ownsAddr(addr) {
+			server.sock.SendTo(&proto.DiscRes{Channel: msg.Channel, Status: 0}, addr)
+			// The client already initiated the disconnect; don't notify it back.
+			conn.close(false)
+		}
+	}
+}
+
+// supportedServices describes the service families this Server answers to, for use in
+// SEARCH_RESPONSE/DESCRIPTION_RESPONSE frames.
+func (server *Server) supportedServices() proto.SupportedServiceFamiliesDIB {
+	return proto.SupportedServiceFamiliesDIB{
+		Families: map[uint8]uint8{ServiceFamilyTunneling: 1},
+	}
+}
+
+// deviceInfo describes this Server's identity, for use in SEARCH_RESPONSE/DESCRIPTION_RESPONSE
+// frames, so that it can be recognized by a Discover call the way any other gateway would be.
+func (server *Server) deviceInfo() proto.DeviceInfoDIB {
+	return proto.DeviceInfoDIB{
+		MediumType:     mediumTypeIP,
+		IndividualAddr: server.config.IndividualAddr,
+		MACAddr:        server.config.MACAddr,
+		Name:           server.config.Name,
+	}
+}
+
+// handleConnect allocates a channel for a new tunnel connection, or rejects it with
+// ConnResBusy if the server is already at MaxConnections.
+func (server *Server) handleConnect(addr net.Addr, req *proto.ConnReq) {
+	server.connsMu.Lock()
+
+	if len(server.conns) >= server.config.MaxConnections {
+		server.connsMu.Unlock()
+		server.sock.SendTo(&proto.ConnRes{Status: proto.ConnResBusy}, addr)
+		return
+	}
+
+	conn := &serverConn{
+		server:     server,
+		channel:    server.allocateChannelLocked(),
+		remote:     addr,
+		lastActive: time.Now(),
+		doneC:      make(chan struct{}),
+	}
+
+	server.conns[conn.channel] = conn
+	server.connsMu.Unlock()
+
+	go server.watchConn(conn)
+
+	server.sock.SendTo(&proto.ConnRes{Channel: conn.channel, Status: proto.ConnResOk}, addr)
+}
+
+// allocateChannelLocked picks the next free channel ID. server.connsMu must be held.
+func (server *Server) allocateChannelLocked() uint8 {
+	for {
+		server.nextChannel++
+
+		if server.nextChannel == 0 {
+			continue
+		}
+
+		if _, taken := server.conns[server.nextChannel]; !taken {
+			return server.nextChannel
+		}
+	}
+}
+
+// watchConn frees conn's channel once it has been idle for longer than HeartbeatTimeout.
+func (server *Server) watchConn(conn *serverConn) {
+	ticker := time.NewTicker(server.config.HeartbeatTimeout / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-server.ctx.Done():
+			return
+
+		case <-conn.doneC:
+			return
+
+		case <-ticker.C:
+			if time.Since(conn.lastActiveAt()) > server.config.HeartbeatTimeout {
+				log(server, "server", "Connection %d timed out", conn.channel)
+				conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// handleTunnelReq validates the sequence number, hands the payload to the Handler, and
+// acknowledges the request.
+func (server *Server) handleTunnelReq(conn *serverConn, req *proto.TunnelReq) {
+	conn.touch()
+
+	conn.seqMu.Lock()
+	expected := conn.inSeq
+	if req.SeqNumber == expected {
+		conn.inSeq++
+	}
+	conn.seqMu.Unlock()
+
+	// Run synchronously, in Serve's single dispatch loop: TunnelReq sequence numbers exist to
+	// guarantee in-order delivery, and a per-frame goroutine would throw that away the moment
+	// one invocation outran the next.
+	if req.SeqNumber == expected && server.handler != nil {
+		server.handler(conn, &req.Payload)
+	}
+
+	server.sock.SendTo(&proto.TunnelRes{Channel: conn.channel, SeqNumber: req.SeqNumber, Status: 0}, conn.remote)
+}
+
+// lookup returns the connection owning channel, or nil if there is none.
+func (server *Server) lookup(channel uint8) *serverConn {
+	server.connsMu.Lock()
+	defer server.connsMu.Unlock()
+
+	return server.conns[channel]
+}
+
+// releaseChannel drops channel from the active connection set.
+func (server *Server) releaseChannel(channel uint8) {
+	server.connsMu.Lock()
+	delete(server.conns, channel)
+	server.connsMu.Unlock()
+}
+
+// serverConn is the Server's bookkeeping for one accepted tunnel connection.
+type serverConn struct {
+	server *Server
+
+	channel uint8
+	remote  net.Addr
+
+	seqMu  sync.Mutex
+	inSeq  uint8
+	outSeq uint8
+
+	activeMu   sync.Mutex
+	lastActive time.Time
+
+	closeOnce sync.Once
+	doneC     chan struct{}
+}
+
+// Channel returns the communication channel ID assigned to this connection.
+func (conn *serverConn) Channel() uint8 {
+	return conn.channel
+}
+
+// Send relays a cEMI frame to this client as a TUNNEL_REQUEST.
+func (conn *serverConn) Send(frame cemi.CEMI) error {
+	conn.seqMu.Lock()
+	seq := conn.outSeq
+	conn.outSeq++
+	conn.seqMu.Unlock()
+
+	return conn.server.sock.SendTo(
+		&proto.TunnelReq{Channel: conn.channel, SeqNumber: seq, Payload: frame},
+		conn.remote,
+	)
+}
+
+// ownsAddr reports whether addr is the address this connection was established from, so that a
+// channel-addressed message (TunnelReq/ConnStateReq/DiscReq) from anyone else can be rejected.
+func (conn *serverConn) ownsAddr(addr net.Addr) bool {
+	return addr.String() == conn.remote.String()
+}
+
+// Close notifies the client with a DiscReq and frees the connection's channel.
+func (conn *serverConn) Close() {
+	conn.close(true)
+}
+
+// close frees the connection's channel and stops watchConn, optionally notifying the client
+// with a DiscReq first. notify is false when the client itself initiated the disconnect, since
+// it already knows and doesn't need telling.
+func (conn *serverConn) close(notify bool) {
+	conn.closeOnce.Do(func() {
+		if notify {
+			conn.server.sock.SendTo(&proto.DiscReq{Channel: conn.channel}, conn.remote)
+		}
+
+		conn.server.releaseChannel(conn.channel)
+		close(conn.doneC)
+	})
+}
+
+// touch marks the connection as having seen activity just now.
+func (conn *serverConn) touch() {
+	conn.activeMu.Lock()
+	conn.lastActive = time.Now()
+	conn.activeMu.Unlock()
+}
+
+// lastActiveAt reports when the connection last saw activity.
+func (conn *serverConn) lastActiveAt() time.Time {
+	conn.activeMu.Lock()
+	defer conn.activeMu.Unlock()
+
+	return conn.lastActive
+}
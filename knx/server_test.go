@@ -0,0 +1,169 @@
+package knx
+
+import (
+	"net"
+	"testing"
+
+	"github.com/vapourismo/knx-go/knx/cemi"
+	"github.com/vapourismo/knx-go/knx/proto"
+)
+
+// fakeServerSocket is a ServerSocket double that records every SendTo call instead of touching
+// the network, so Server logic can be exercised without a real UDP socket.
+type fakeServerSocket struct {
+	inbound chan ServerSocketMsg
+	sent    []interface{}
+}
+
+func newFakeServerSocket() *fakeServerSocket {
+	return &fakeServerSocket{inbound: make(chan ServerSocketMsg)}
+}
+
+func (sock *fakeServerSocket) Inbound() <-chan ServerSocketMsg { return sock.inbound }
+
+func (sock *fakeServerSocket) SendTo(payload interface{}, addr net.Addr) error {
+	sock.sent = append(sock.sent, payload)
+	return nil
+}
+
+func (sock *fakeServerSocket) Close() error { return nil }
+
+func TestCheckServerConfigClampsMaxConnections(t *testing.T) {
+	config := checkServerConfig(ServerConfig{MaxConnections: 1000})
+
+	if config.MaxConnections != maxPossibleConnections {
+		t.Fatalf("MaxConnections = %d, want %d", config.MaxConnections, maxPossibleConnections)
+	}
+}
+
+func TestCheckServerConfigDefaultsZeroValues(t *testing.T) {
+	config := checkServerConfig(ServerConfig{})
+
+	if config.MaxConnections != defaultMaxConnections {
+		t.Fatalf("MaxConnections = %d, want default %d", config.MaxConnections, defaultMaxConnections)
+	}
+
+	if config.HeartbeatTimeout != ConnectionTimeout {
+		t.Fatalf("HeartbeatTimeout = %v, want default %v", config.HeartbeatTimeout, ConnectionTimeout)
+	}
+}
+
+func TestServerAllocateChannelLockedSkipsZero(t *testing.T) {
+	server := &Server{conns: make(map[uint8]*serverConn), nextChannel: 255}
+
+	channel := server.allocateChannelLocked()
+
+	if channel == 0 {
+		t.Fatalf("allocateChannelLocked returned reserved channel 0")
+	}
+}
+
+func TestServerAllocateChannelLockedReusesFreedChannel(t *testing.T) {
+	server := &Server{conns: make(map[uint8]*serverConn)}
+
+	first := server.allocateChannelLocked()
+	server.conns[first] = &serverConn{}
+	delete(server.conns, first)
+
+	second := server.allocateChannelLocked()
+	if second != first {
+		t.Fatalf("allocateChannelLocked = %d, want freed channel %d reused", second, first)
+	}
+}
+
+func TestServerDeviceInfoReflectsConfig(t *testing.T) {
+	server := &Server{
+		config: ServerConfig{
+			Name:           "gateway",
+			IndividualAddr: 0x1101,
+			MACAddr:        [6]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06},
+		},
+	}
+
+	info := server.deviceInfo()
+
+	if info.MediumType != mediumTypeIP {
+		t.Fatalf("MediumType = %#x, want %#x", info.MediumType, mediumTypeIP)
+	}
+
+	if info.Name != "gateway" {
+		t.Fatalf("Name = %q, want %q", info.Name, "gateway")
+	}
+
+	if info.IndividualAddr != 0x1101 {
+		t.Fatalf("IndividualAddr = %#x, want %#x", info.IndividualAddr, 0x1101)
+	}
+
+	if info.MACAddr != server.config.MACAddr {
+		t.Fatalf("MACAddr = %v, want %v", info.MACAddr, server.config.MACAddr)
+	}
+}
+
+// TestHandleTunnelReqRunsHandlerSynchronouslyInOrder guards against handleTunnelReq going back to
+// dispatching the Handler via a per-frame goroutine: an in-order frame must be fully handled, in
+// order, before handleTunnelReq returns, and a duplicate/out-of-order frame must not reach the
+// Handler at all.
+func TestHandleTunnelReqRunsHandlerSynchronouslyInOrder(t *testing.T) {
+	sock := newFakeServerSocket()
+
+	var seen []uint8
+
+	server := &Server{
+		sock: sock,
+		handler: func(conn ServerConn, frame *cemi.CEMI) {
+			seen = append(seen, conn.Channel())
+		},
+	}
+
+	conn := &serverConn{server: server, channel: 1, remote: &net.UDPAddr{}}
+
+	server.handleTunnelReq(conn, &proto.TunnelReq{Channel: 1, SeqNumber: 0})
+	server.handleTunnelReq(conn, &proto.TunnelReq{Channel: 1, SeqNumber: 0}) // retransmit, must be ignored
+	server.handleTunnelReq(conn, &proto.TunnelReq{Channel: 1, SeqNumber: 1})
+
+	if len(seen) != 2 {
+		t.Fatalf("handler invoked %d times, want 2 (duplicate must be dropped)", len(seen))
+	}
+
+	if len(sock.sent) != 3 {
+		t.Fatalf("TunnelRes sent %d times, want 3 (every request, including the duplicate, is acked)", len(sock.sent))
+	}
+}
+
+// TestDispatchRejectsSpoofedAddr guards against a channel-addressed message from a sender other
+// than the one a connection was established with: channel IDs are a single byte, so honoring
+// them without checking the sender would let any host on the LAN hijack someone else's channel.
+func TestDispatchRejectsSpoofedAddr(t *testing.T) {
+	sock := newFakeServerSocket()
+
+	var seen []uint8
+
+	server := &Server{
+		sock:  sock,
+		conns: make(map[uint8]*serverConn),
+		handler: func(conn ServerConn, frame *cemi.CEMI) {
+			seen = append(seen, conn.Channel())
+		},
+	}
+
+	owner := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 3671}
+	conn := &serverConn{server: server, channel: 1, remote: owner}
+	server.conns[1] = conn
+
+	attacker := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 66), Port: 3671}
+	server.dispatch(attacker, &proto.TunnelReq{Channel: 1, SeqNumber: 0})
+
+	if len(seen) != 0 {
+		t.Fatalf("handler invoked by a request from a spoofed address, want it dropped")
+	}
+
+	if len(sock.sent) != 0 {
+		t.Fatalf("server responded to a spoofed-address request, want it silently dropped")
+	}
+
+	server.dispatch(owner, &proto.TunnelReq{Channel: 1, SeqNumber: 0})
+
+	if len(seen) != 1 {
+		t.Fatalf("handler not invoked for a request from the connection's real address")
+	}
+}
@@ -0,0 +1,82 @@
+package knx
+
+import (
+	"errors"
+	"net"
+
+	"github.com/vapourismo/knx-go/knx/proto"
+)
+
+// MulticastSocket is a Socket that communicates over a KNXnet/IP Routing multicast group. Unlike
+// the tunneling socket, it is not addressed to a single gateway: every datagram on the group is
+// visible to every router that joined it, and there is no connection handshake.
+type MulticastSocket struct {
+	conn      *net.UDPConn
+	groupAddr *net.UDPAddr
+
+	inbound chan interface{}
+}
+
+// NewMulticastSocket joins the given multicast group address (e.g. "224.0.23.12:3671") and
+// returns a socket that relays KNXnet/IP Routing frames on it, reusing the same wire codec as
+// the tunneling sockets.
+func NewMulticastSocket(multicastAddr string) (*MulticastSocket, error) {
+	groupAddr, err := net.ResolveUDPAddr("udp4", multicastAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.ListenMulticastUDP("udp4", nil, groupAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	sock := &MulticastSocket{
+		conn:      conn,
+		groupAddr: groupAddr,
+		inbound:   make(chan interface{}),
+	}
+
+	go sock.serveReceive()
+
+	return sock, nil
+}
+
+// serveReceive decodes datagrams off the multicast group and forwards the ones that parse into a
+// known KNXnet/IP frame. The group is shared with every other router on it, so a single
+// malformed or unrelated datagram from any of them must not end reception for this one; only a
+// genuine I/O error on the socket does.
+func (sock *MulticastSocket) serveReceive() {
+	defer close(sock.inbound)
+
+	for {
+		msg, err := proto.ReadFrom(sock.conn)
+		if err != nil {
+			var frameErr *proto.FrameError
+			if errors.As(err, &frameErr) {
+				continue
+			}
+
+			return
+		}
+
+		sock.inbound <- msg
+	}
+}
+
+// Send encodes payload as a KNXnet/IP frame and writes it to the multicast group. conn is
+// unconnected (it was obtained through net.ListenMulticastUDP, which has no default remote
+// address), so the group address must be supplied on every write.
+func (sock *MulticastSocket) Send(payload interface{}) error {
+	return proto.WriteToAddr(sock.conn, payload, sock.groupAddr)
+}
+
+// Inbound retrieves the channel which transmits incoming frames.
+func (sock *MulticastSocket) Inbound() <-chan interface{} {
+	return sock.inbound
+}
+
+// Close leaves the multicast group.
+func (sock *MulticastSocket) Close() error {
+	return sock.conn.Close()
+}
@@ -0,0 +1,89 @@
+package knx
+
+import (
+	"errors"
+	"net"
+
+	"github.com/vapourismo/knx-go/knx/proto"
+)
+
+// ServerSocketMsg pairs an incoming KNXnet/IP frame with the address it arrived from, since a
+// Server's socket is shared by every connected client instead of being dedicated to one peer.
+type ServerSocketMsg struct {
+	Addr    net.Addr
+	Payload interface{}
+}
+
+// ServerSocket is the socket abstraction a Server uses to talk to potentially many clients over
+// a single UDP endpoint.
+type ServerSocket interface {
+	// Inbound retrieves the channel which transmits incoming frames.
+	Inbound() <-chan ServerSocketMsg
+
+	// SendTo writes payload to a specific client.
+	SendTo(payload interface{}, addr net.Addr) error
+
+	// Close shuts down the socket.
+	Close() error
+}
+
+// udpServerSocket is the default ServerSocket, backed by a single unconnected UDP socket.
+type udpServerSocket struct {
+	conn *net.UDPConn
+
+	inbound chan ServerSocketMsg
+}
+
+// NewServerSocket binds a UDP socket at bindAddr (typically ":3671") for use by a Server.
+func NewServerSocket(bindAddr string) (ServerSocket, error) {
+	addr, err := net.ResolveUDPAddr("udp4", bindAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.ListenUDP("udp4", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	sock := &udpServerSocket{
+		conn:    conn,
+		inbound: make(chan ServerSocketMsg),
+	}
+
+	go sock.serveReceive()
+
+	return sock, nil
+}
+
+// serveReceive decodes datagrams as they arrive and forwards the ones that parse into a known
+// KNXnet/IP frame, tagged with their sender's address. The socket is shared by every client the
+// server has, so a single malformed or unrelated datagram from anyone on the LAN must not end
+// reception for everyone else; only a genuine socket error ends the loop.
+func (sock *udpServerSocket) serveReceive() {
+	defer close(sock.inbound)
+
+	for {
+		msg, addr, err := proto.ReadFromAddr(sock.conn)
+		if err != nil {
+			var frameErr *proto.FrameError
+			if errors.As(err, &frameErr) {
+				continue
+			}
+
+			return
+		}
+
+		sock.inbound <- ServerSocketMsg{Addr: addr, Payload: msg}
+	}
+}
+
+// SendTo encodes payload as a KNXnet/IP frame and writes it to addr.
+func (sock *udpServerSocket) SendTo(payload interface{}, addr net.Addr) error {
+	return proto.WriteToAddr(sock.conn, payload, addr)
+}
+
+// Close shuts down the socket.
+func (sock *udpServerSocket) Close() error {
+	return sock.conn.Close()
+}